@@ -0,0 +1,113 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Certificate is a type to represent a Certificate from ACME
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateSpec   `json:"spec,omitempty"`
+	Status CertificateStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateList is a list of Certificates
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Certificate `json:"items"`
+}
+
+// CertificateSpec defines the desired state of Certificate
+type CertificateSpec struct {
+	// CommonName is a common name to be used on the Certificate
+	CommonName string `json:"commonName"`
+
+	// DNSNames is a list of subject alt names to be used on the Certificate
+	DNSNames []string `json:"dnsNames"`
+
+	// SecretName is the name of the secret resource to store this secret in
+	SecretName string `json:"secretName"`
+
+	// IssuerRef is a reference to the issuer for this certificate. If the
+	// Kind field is set to "ClusterIssuer", the named ClusterIssuer is
+	// used. Otherwise, an Issuer of the given name is looked up in the
+	// same namespace as the Certificate.
+	IssuerRef ObjectReference `json:"issuerRef"`
+
+	// IsCA will mark this Certificate as valid for certificate signing
+	IsCA bool `json:"isCA,omitempty"`
+
+	// Duration is the period of validity to request for this certificate.
+	// If unset, a default of 90 days will be requested by the issuer.
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// RenewBefore is how long before the currently issued certificate's
+	// expiry cert-manager should renew the certificate. If unset, the
+	// owning Issuer's RenewalPolicy is used when it has one; otherwise
+	// renewal is at a random point between 60% and 90% of the issued
+	// certificate's validity period.
+	RenewBefore metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertificateStatus defines the observed state of Certificate
+type CertificateStatus struct {
+	Conditions []CertificateCondition `json:"conditions,omitempty"`
+
+	// LastFailureTime is set only if the latest issuance for this
+	// Certificate failed and contains the time of the failure. If an
+	// issuance has succeeded since the last failure, this field will be
+	// cleared.
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// NotAfter is the timestamp at which the current issued certificate
+	// for this Certificate resource expires.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// CertificateConditionType represents a Certificate condition value
+type CertificateConditionType string
+
+const (
+	// CertificateConditionReady indicates that a Certificate is ready for
+	// use. This is defined as:
+	// - The target secret exists
+	// - The target secret contains a certificate that has not expired
+	// - The target secret contains a private key valid for the certificate
+	CertificateConditionReady CertificateConditionType = "Ready"
+)
+
+// CertificateCondition contains condition information for a Certificate
+type CertificateCondition struct {
+	Type CertificateConditionType `json:"type"`
+
+	Status ConditionStatus `json:"status"`
+
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	Reason string `json:"reason,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+// ConditionStatus represents a condition's status
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// ObjectReference is a reference to an object with a given name and kind.
+type ObjectReference struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
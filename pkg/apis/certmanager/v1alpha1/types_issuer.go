@@ -0,0 +1,123 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Issuer is a type to represent an Issuer in cert-manager
+type Issuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IssuerSpec   `json:"spec,omitempty"`
+	Status IssuerStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IssuerList is a list of Issuers
+type IssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Issuer `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterIssuer is a type to represent a ClusterIssuer in cert-manager
+type ClusterIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IssuerSpec   `json:"spec,omitempty"`
+	Status IssuerStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterIssuerList is a list of ClusterIssuers
+type ClusterIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterIssuer `json:"items"`
+}
+
+// IssuerSpec is the specification of an Issuer. This includes any
+// configuration required for the issuer.
+type IssuerSpec struct {
+	IssuerConfig `json:",inline"`
+
+	// RenewalPolicy tunes how Certificates issued by this Issuer are
+	// scheduled for renewal. If unset, the certificates controller renews
+	// at a random point between 60% and 90% of each issued certificate's
+	// validity period (its built-in tiers are not used), so adding this field to an
+	// existing Issuer is a no-op until it is explicitly populated - no
+	// migration step is required.
+	RenewalPolicy *RenewalPolicy `json:"renewalPolicy,omitempty"`
+}
+
+// IssuerConfig contains the configuration for the issuer backend selected
+type IssuerConfig struct {
+	ACME *ACMEIssuer `json:"acme,omitempty"`
+	CA   *CAIssuer   `json:"ca,omitempty"`
+}
+
+// RenewalPolicy bounds the renewal lead time the certificates controller
+// computes for Certificates issued by this Issuer, on top of its
+// built-in tiers (e.g. renewing 4 months before expiry for ~1y+
+// certificates, down to 1 day before for short-lived ones). This lets
+// operators mixing short-lived ACME issuers with long-lived internal CAs
+// configure an appropriate cadence per Issuer rather than per
+// Certificate.
+type RenewalPolicy struct {
+	// MinRenewBefore is the shortest renewal lead time to use for
+	// Certificates issued by this Issuer, regardless of tier. A zero
+	// value means no minimum is enforced.
+	MinRenewBefore metav1.Duration `json:"minRenewBefore,omitempty"`
+
+	// MaxRenewBefore is the longest renewal lead time to use for
+	// Certificates issued by this Issuer, regardless of tier. A zero
+	// value means no maximum is enforced.
+	MaxRenewBefore metav1.Duration `json:"maxRenewBefore,omitempty"`
+}
+
+// ACMEIssuer contains the specification for an ACME issuer
+type ACMEIssuer struct {
+	Email  string `json:"email"`
+	Server string `json:"server"`
+}
+
+// CAIssuer contains the specification for a CA issuer
+type CAIssuer struct {
+	SecretName string `json:"secretName"`
+}
+
+// IssuerStatus contains status information about an Issuer
+type IssuerStatus struct {
+	Conditions []IssuerCondition `json:"conditions,omitempty"`
+}
+
+// IssuerConditionType represents an Issuer condition value
+type IssuerConditionType string
+
+const (
+	// IssuerConditionReady indicates that an Issuer is ready for use
+	IssuerConditionReady IssuerConditionType = "Ready"
+)
+
+// IssuerCondition contains condition information for an Issuer
+type IssuerCondition struct {
+	Type IssuerConditionType `json:"type"`
+
+	Status ConditionStatus `json:"status"`
+
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	Reason string `json:"reason,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
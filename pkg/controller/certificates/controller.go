@@ -0,0 +1,48 @@
+package certificates
+
+import (
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha1"
+)
+
+// Controller watches Certificate resources and ensures that the x509
+// certificate stored in each Certificate's target Secret is kept valid,
+// issuing and renewing it ahead of expiry as required.
+type Controller struct {
+	client              cmclient.Interface
+	certificateLister   cmlisters.CertificateLister
+	secretLister        corelisters.SecretLister
+	issuerLister        cmlisters.IssuerLister
+	clusterIssuerLister cmlisters.ClusterIssuerLister
+	recorder            record.EventRecorder
+}
+
+// Run starts the controller's background loops, which run until stopCh
+// is closed, and returns without waiting for them. Currently this is
+// just the periodic metrics scraper; as other background loops are added
+// they should be started here too.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	go c.runMetricsScraper(stopCh)
+}
+
+// New returns a new certificates Controller.
+func New(
+	client cmclient.Interface,
+	certificateLister cmlisters.CertificateLister,
+	secretLister corelisters.SecretLister,
+	issuerLister cmlisters.IssuerLister,
+	clusterIssuerLister cmlisters.ClusterIssuerLister,
+	recorder record.EventRecorder,
+) *Controller {
+	return &Controller{
+		client:              client,
+		certificateLister:   certificateLister,
+		secretLister:        secretLister,
+		issuerLister:        issuerLister,
+		clusterIssuerLister: clusterIssuerLister,
+		recorder:            recorder,
+	}
+}
@@ -0,0 +1,71 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+// metricsScrapeInterval is how often runMetricsScraper re-emits gauges
+// for every known Certificate, analogous to a standalone cert-monitor
+// loop. This covers Certificates that haven't had a reconcile fire
+// recently enough for updateMetrics to have kept their gauges current.
+const metricsScrapeInterval = 30 * time.Minute
+
+// runMetricsScraper re-emits certificate metrics for every Certificate on
+// a fixed interval, until stopCh is closed. It is intended to be run in
+// its own goroutine alongside the controller's regular workqueue
+// processing.
+func (c *Controller) runMetricsScraper(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(metricsScrapeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			c.scrapeMetricsOnce()
+		}
+	}
+}
+
+func (c *Controller) scrapeMetricsOnce() {
+	crts, err := c.certificateLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("error listing certificates during metrics scrape: %v", err)
+		return
+	}
+
+	for _, crt := range crts {
+		x509Cert, err := c.currentX509Certificate(crt)
+		if err != nil {
+			glog.Errorf("error reading issued certificate for %s/%s during metrics scrape: %v", crt.Namespace, crt.Name, err)
+			continue
+		}
+		if x509Cert == nil {
+			continue
+		}
+		c.updateMetrics(x509Cert, crt)
+	}
+}
+
+// currentX509Certificate reads and parses the x509 certificate currently
+// stored in crt's target Secret, returning nil if it does not yet exist.
+func (c *Controller) currentX509Certificate(crt *v1alpha1.Certificate) (*x509.Certificate, error) {
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		return nil, nil
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
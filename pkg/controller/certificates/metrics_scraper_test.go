@@ -0,0 +1,199 @@
+package certificates
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha1"
+)
+
+// fakeSecretLister is a minimal corelisters.SecretLister backed by a
+// namespace/name-keyed map, for exercising currentX509Certificate without
+// a real informer.
+type fakeSecretLister struct {
+	secrets map[string]*corev1.Secret
+}
+
+func (f fakeSecretLister) List(selector labels.Selector) ([]*corev1.Secret, error) { return nil, nil }
+
+func (f fakeSecretLister) Secrets(namespace string) corelisters.SecretNamespaceLister {
+	return fakeSecretNamespaceLister{namespace: namespace, secrets: f.secrets}
+}
+
+type fakeSecretNamespaceLister struct {
+	namespace string
+	secrets   map[string]*corev1.Secret
+}
+
+func (f fakeSecretNamespaceLister) List(selector labels.Selector) ([]*corev1.Secret, error) {
+	return nil, nil
+}
+
+func (f fakeSecretNamespaceLister) Get(name string) (*corev1.Secret, error) {
+	secret, ok := f.secrets[f.namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+	}
+	return secret, nil
+}
+
+// fakeCertificateLister is a minimal cmlisters.CertificateLister backed by
+// a flat slice, for exercising scrapeMetricsOnce without a real informer.
+type fakeCertificateLister struct {
+	certs []*v1alpha1.Certificate
+	err   error
+}
+
+func (f fakeCertificateLister) List(selector labels.Selector) ([]*v1alpha1.Certificate, error) {
+	return f.certs, f.err
+}
+
+func (f fakeCertificateLister) Certificates(namespace string) cmlisters.CertificateNamespaceLister {
+	return fakeCertificateNamespaceLister{}
+}
+
+type fakeCertificateNamespaceLister struct{}
+
+func (f fakeCertificateNamespaceLister) List(selector labels.Selector) ([]*v1alpha1.Certificate, error) {
+	return nil, nil
+}
+
+func (f fakeCertificateNamespaceLister) Get(name string) (*v1alpha1.Certificate, error) {
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "certificates"}, name)
+}
+
+// selfSignedCertPEM generates a throwaway self-signed certificate for use
+// as valid tls.crt Secret data.
+func selfSignedCertPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour * 24 * 90),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCurrentX509Certificate(t *testing.T) {
+	crt := &v1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-1"},
+		Spec:       v1alpha1.CertificateSpec{SecretName: "cert-1-tls"},
+	}
+
+	t.Run("missing secret", func(t *testing.T) {
+		c := &Controller{secretLister: fakeSecretLister{secrets: map[string]*corev1.Secret{}}}
+
+		x509Cert, err := c.currentX509Certificate(crt)
+		if err == nil {
+			t.Fatal("expected an error for a missing secret")
+		}
+		if x509Cert != nil {
+			t.Errorf("expected a nil certificate, got %v", x509Cert)
+		}
+	})
+
+	t.Run("secret has no PEM-encoded data yet", func(t *testing.T) {
+		c := &Controller{secretLister: fakeSecretLister{secrets: map[string]*corev1.Secret{
+			"ns1/cert-1-tls": {Data: map[string][]byte{}},
+		}}}
+
+		x509Cert, err := c.currentX509Certificate(crt)
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if x509Cert != nil {
+			t.Errorf("expected a nil certificate, got %v", x509Cert)
+		}
+	})
+
+	t.Run("PEM block does not contain a parseable certificate", func(t *testing.T) {
+		garbage := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a real certificate")})
+		c := &Controller{secretLister: fakeSecretLister{secrets: map[string]*corev1.Secret{
+			"ns1/cert-1-tls": {Data: map[string][]byte{"tls.crt": garbage}},
+		}}}
+
+		x509Cert, err := c.currentX509Certificate(crt)
+		if err == nil {
+			t.Fatal("expected a parse error for an unparseable certificate")
+		}
+		if x509Cert != nil {
+			t.Errorf("expected a nil certificate, got %v", x509Cert)
+		}
+	})
+
+	t.Run("valid issued certificate", func(t *testing.T) {
+		c := &Controller{secretLister: fakeSecretLister{secrets: map[string]*corev1.Secret{
+			"ns1/cert-1-tls": {Data: map[string][]byte{"tls.crt": selfSignedCertPEM(t)}},
+		}}}
+
+		x509Cert, err := c.currentX509Certificate(crt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if x509Cert == nil {
+			t.Fatal("expected a parsed certificate, got nil")
+		}
+	})
+}
+
+func TestScrapeMetricsOnce(t *testing.T) {
+	t.Run("lister error is logged and does not panic", func(t *testing.T) {
+		c := &Controller{certificateLister: fakeCertificateLister{err: apierrors.NewInternalError(errors.New("boom"))}}
+		c.scrapeMetricsOnce()
+	})
+
+	t.Run("per-certificate errors are skipped, not fatal to the scrape", func(t *testing.T) {
+		withMissingSecret := &v1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-missing-secret"},
+			Spec:       v1alpha1.CertificateSpec{SecretName: "does-not-exist"},
+		}
+		notYetIssued := &v1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-not-issued"},
+			Spec:       v1alpha1.CertificateSpec{SecretName: "cert-not-issued-tls"},
+		}
+		issued := &v1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-issued"},
+			Spec:       v1alpha1.CertificateSpec{SecretName: "cert-issued-tls", IssuerRef: v1alpha1.ObjectReference{Name: "issuer-1"}},
+		}
+
+		c := &Controller{
+			certificateLister: fakeCertificateLister{certs: []*v1alpha1.Certificate{withMissingSecret, notYetIssued, issued}},
+			secretLister: fakeSecretLister{secrets: map[string]*corev1.Secret{
+				"ns1/cert-not-issued-tls": {Data: map[string][]byte{}},
+				"ns1/cert-issued-tls":     {Data: map[string][]byte{"tls.crt": selfSignedCertPEM(t)}},
+			}},
+		}
+
+		// Should not panic despite one Certificate's secret being missing
+		// entirely.
+		c.scrapeMetricsOnce()
+	})
+}
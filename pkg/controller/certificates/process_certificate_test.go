@@ -0,0 +1,105 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProcessCertificateShortDurationEvent(t *testing.T) {
+	notBefore := time.Now()
+	notAfter := notBefore.Add(time.Hour * 24 * 90)
+	x509Cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter, SerialNumber: big.NewInt(1)}
+
+	newCert := func() *v1alpha1.Certificate {
+		return &v1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-1"},
+			Spec: v1alpha1.CertificateSpec{
+				// Requested 120d, issued only 90d: meaningfully short.
+				Duration: metav1.Duration{Duration: time.Hour * 24 * 120},
+			},
+		}
+	}
+
+	t.Run("fires once for a freshly observed certificate", func(t *testing.T) {
+		fakeRecorder := record.NewFakeRecorder(1)
+		c := &Controller{recorder: fakeRecorder}
+		crt := newCert()
+
+		c.ProcessCertificate(crt, x509Cert)
+
+		select {
+		case <-fakeRecorder.Events:
+		default:
+			t.Error("expected an event to be recorded for a freshly observed short-duration certificate")
+		}
+	})
+
+	t.Run("does not re-fire on subsequent observations of the same certificate", func(t *testing.T) {
+		fakeRecorder := record.NewFakeRecorder(1)
+		c := &Controller{recorder: fakeRecorder}
+		crt := newCert()
+
+		c.ProcessCertificate(crt, x509Cert)
+		<-fakeRecorder.Events // drain the first (expected) event
+
+		// Simulate the periodic metrics scraper, or a routine reconcile,
+		// re-processing the same already-observed certificate.
+		c.ProcessCertificate(crt, x509Cert)
+		c.ProcessCertificate(crt, x509Cert)
+
+		select {
+		case event := <-fakeRecorder.Events:
+			t.Errorf("expected no further events for an already-observed certificate, got %q", event)
+		default:
+		}
+	})
+
+	t.Run("does not re-fire across independently-fetched copies once persisted", func(t *testing.T) {
+		// A real reconcile never reuses the same *v1alpha1.Certificate
+		// pointer across passes - each one re-fetches a fresh copy from the
+		// informer lister. This simulates that by running ProcessCertificate
+		// against two separate objects that share the persisted
+		// Status.NotAfter a first reconcile would have written back via
+		// UpdateStatus, proving the gate keys off persisted state rather
+		// than in-memory pointer identity.
+		fakeRecorder := record.NewFakeRecorder(1)
+		c := &Controller{recorder: fakeRecorder}
+		firstPass := newCert()
+
+		c.ProcessCertificate(firstPass, x509Cert)
+		<-fakeRecorder.Events // drain the first (expected) event
+
+		secondPass := newCert()
+		secondPass.Status.NotAfter = firstPass.Status.NotAfter
+
+		c.ProcessCertificate(secondPass, x509Cert)
+
+		select {
+		case event := <-fakeRecorder.Events:
+			t.Errorf("expected no event for a certificate whose persisted status already reflects this issuance, got %q", event)
+		default:
+		}
+	})
+
+	t.Run("does not fire when the issued duration matches the request", func(t *testing.T) {
+		fakeRecorder := record.NewFakeRecorder(1)
+		c := &Controller{recorder: fakeRecorder}
+		crt := newCert()
+		crt.Spec.Duration = metav1.Duration{Duration: time.Hour * 24 * 90}
+
+		c.ProcessCertificate(crt, x509Cert)
+
+		select {
+		case event := <-fakeRecorder.Events:
+			t.Errorf("expected no event when issued duration matches the request, got %q", event)
+		default:
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package certificates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveRenewBefore(t *testing.T) {
+	tests := []struct {
+		desc            string
+		certDuration    time.Duration
+		specRenewBefore time.Duration
+		policy          *v1alpha1.RenewalPolicy
+		expectOk        bool
+		expectedExpiry  time.Duration
+	}{
+		{
+			desc:            "explicit Spec.RenewBefore wins over any Issuer policy",
+			certDuration:    time.Hour * 24 * 365,
+			specRenewBefore: time.Hour * 24 * 10,
+			policy:          &v1alpha1.RenewalPolicy{MinRenewBefore: metav1.Duration{Duration: time.Hour * 24 * 200}},
+			expectOk:        true,
+			expectedExpiry:  time.Hour * 24 * 10,
+		},
+		{
+			desc:           "no Issuer policy and no Spec.RenewBefore: caller falls back to jitter",
+			certDuration:   time.Hour * 24 * 365,
+			policy:         nil,
+			expectOk:       false,
+			expectedExpiry: 0,
+		},
+		{
+			desc:           "tier: >=1y issued lifetime renews 4 months before",
+			certDuration:   time.Hour * 24 * 400,
+			policy:         &v1alpha1.RenewalPolicy{},
+			expectOk:       true,
+			expectedExpiry: time.Hour * 24 * 120,
+		},
+		{
+			desc:           "tier: >=90d issued lifetime renews 30 days before",
+			certDuration:   time.Hour * 24 * 90,
+			policy:         &v1alpha1.RenewalPolicy{},
+			expectOk:       true,
+			expectedExpiry: time.Hour * 24 * 30,
+		},
+		{
+			desc:           "tier: >=7d issued lifetime renews 1 day before",
+			certDuration:   time.Hour * 24 * 7,
+			policy:         &v1alpha1.RenewalPolicy{},
+			expectOk:       true,
+			expectedExpiry: time.Hour * 24 * 1,
+		},
+		{
+			desc:           "tier: <7d issued lifetime renews at 2/3 of lifetime",
+			certDuration:   time.Hour * 24 * 2,
+			policy:         &v1alpha1.RenewalPolicy{},
+			expectOk:       true,
+			expectedExpiry: time.Hour * 24 * 2 / 3,
+		},
+		{
+			desc:           "MinRenewBefore raises a tier default that is too short",
+			certDuration:   time.Hour * 24 * 90,
+			policy:         &v1alpha1.RenewalPolicy{MinRenewBefore: metav1.Duration{Duration: time.Hour * 24 * 45}},
+			expectOk:       true,
+			expectedExpiry: time.Hour * 24 * 45,
+		},
+		{
+			desc:           "MaxRenewBefore caps a tier default that is too long",
+			certDuration:   time.Hour * 24 * 400,
+			policy:         &v1alpha1.RenewalPolicy{MaxRenewBefore: metav1.Duration{Duration: time.Hour * 24 * 30}},
+			expectOk:       true,
+			expectedExpiry: time.Hour * 24 * 30,
+		},
+	}
+	for k, v := range tests {
+		crt := &v1alpha1.Certificate{
+			Spec: v1alpha1.CertificateSpec{
+				RenewBefore: metav1.Duration{Duration: v.specRenewBefore},
+			},
+		}
+		renewBefore, ok := resolveRenewBefore(crt, v.certDuration, v.policy)
+		if ok != v.expectOk {
+			t.Errorf("test # %d - %s: got ok=%v, expected %v", k, v.desc, ok, v.expectOk)
+			continue
+		}
+		if ok && renewBefore != v.expectedExpiry {
+			t.Errorf("test # %d - %s: got %v, expected %v", k, v.desc, renewBefore, v.expectedExpiry)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	certmetrics "github.com/jetstack/cert-manager/pkg/metrics/certificates"
+)
+
+const (
+	// issuedDurationShorterThanRequestedThreshold is how much shorter
+	// than Spec.Duration an issued certificate's actual validity period
+	// may be before it is considered meaningfully short and a Warning
+	// Event is recorded against the Certificate.
+	issuedDurationShorterThanRequestedThreshold = 0.05
+
+	// ReasonIssuedDurationShorterThanRequested is the Event reason used
+	// when an issuer returns a certificate whose validity period is
+	// shorter than the Certificate's requested Spec.Duration.
+	ReasonIssuedDurationShorterThanRequested = "IssuedDurationShorterThanRequested"
+)
+
+// now is overridden in tests to return a fixed point in time.
+var now = time.Now
+
+const (
+	// renewalWindowMin and renewalWindowMax bound the "soft expiry"
+	// window: in the absence of an explicit RenewBefore, a Certificate is
+	// renewed at a random point within this fraction of its validity
+	// lifetime. This spreads renewal of a large fleet of Certificates
+	// that were all issued around the same time, rather than having them
+	// all attempt to re-issue in the same reconcile window.
+	renewalWindowMin = 0.60
+	renewalWindowMax = 0.90
+)
+
+// calculateTimeBeforeExpiry returns the Duration before x509Cert's
+// NotAfter at which crt should be renewed.
+//
+// If crt.Spec.RenewBefore is set, it is honoured exactly: this is the
+// escape hatch for users who need deterministic renewal timing rather
+// than a randomized one. If it is unset and crt's owning Issuer has a
+// RenewalPolicy, the policy's tiered renewBefore is used in the same way.
+// A renewBefore longer than the certificate's validity lifetime is
+// replaced by renewal at 2/3 of that lifetime. Otherwise, a renewal time
+// is chosen uniformly at random within the 60%-90% soft-expiry window of
+// the certificate's validity lifetime. The choice is seeded from the Certificate's
+// namespace, name and issued serial number so it is stable across
+// controller restarts rather than being recomputed - and potentially
+// flapping - on every resync.
+func (c *Controller) calculateTimeBeforeExpiry(x509Cert *x509.Certificate, crt *v1alpha1.Certificate) time.Duration {
+	certDuration := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
+
+	if renewBefore, ok := resolveRenewBefore(crt, certDuration, c.renewalPolicyFor(crt)); ok {
+		if renewBefore > certDuration {
+			return certDuration / 3
+		}
+		return renewBefore
+	}
+
+	return calculateJitteredTimeBeforeExpiry(crt, x509Cert, certDuration)
+}
+
+// issuerRenewalTiers are the built-in default renewal lead times used
+// when a Certificate's owning Issuer has a RenewalPolicy but no
+// applicable MinRenewBefore/MaxRenewBefore override, keyed by the issued
+// certificate's validity lifetime. Tiers are evaluated in order and the
+// first one the certificate's lifetime meets or exceeds wins;
+// certificates shorter than every tier are renewed at 2/3 of their own
+// lifetime, i.e. 1/3 of it before expiry.
+var issuerRenewalTiers = []struct {
+	minLifetime time.Duration
+	renewBefore time.Duration
+}{
+	{minLifetime: 365 * 24 * time.Hour, renewBefore: 4 * 30 * 24 * time.Hour}, // >=1y: renew 4 months before
+	{minLifetime: 90 * 24 * time.Hour, renewBefore: 30 * 24 * time.Hour},      // >=90d: renew 30d before
+	{minLifetime: 7 * 24 * time.Hour, renewBefore: 24 * time.Hour},            // >=7d: renew 1d before
+}
+
+// renewBeforeForTier returns the tiered default renewal lead time for a
+// certificate with the given validity lifetime.
+func renewBeforeForTier(certDuration time.Duration) time.Duration {
+	for _, tier := range issuerRenewalTiers {
+		if certDuration >= tier.minLifetime {
+			return tier.renewBefore
+		}
+	}
+	return certDuration / 3
+}
+
+// resolveRenewBefore determines the renewBefore duration to apply for
+// crt, in priority order: an explicit crt.Spec.RenewBefore always wins;
+// otherwise, if policy is non-nil (the owning Issuer has a
+// RenewalPolicy), the tiered default for certDuration is used, clamped
+// to policy's Min/MaxRenewBefore. A returned ok of false means neither
+// applies and the caller should fall back to its own default (today,
+// the jittered soft-expiry window).
+func resolveRenewBefore(crt *v1alpha1.Certificate, certDuration time.Duration, policy *v1alpha1.RenewalPolicy) (time.Duration, bool) {
+	if crt.Spec.RenewBefore.Duration > 0 {
+		return crt.Spec.RenewBefore.Duration, true
+	}
+
+	if policy == nil {
+		return 0, false
+	}
+
+	renewBefore := renewBeforeForTier(certDuration)
+	if policy.MinRenewBefore.Duration > 0 && renewBefore < policy.MinRenewBefore.Duration {
+		renewBefore = policy.MinRenewBefore.Duration
+	}
+	if policy.MaxRenewBefore.Duration > 0 && renewBefore > policy.MaxRenewBefore.Duration {
+		renewBefore = policy.MaxRenewBefore.Duration
+	}
+	return renewBefore, true
+}
+
+// renewalPolicyFor looks up crt's owning Issuer or ClusterIssuer and
+// returns its RenewalPolicy, or nil if it has none configured, or the
+// issuer can't be found.
+func (c *Controller) renewalPolicyFor(crt *v1alpha1.Certificate) *v1alpha1.RenewalPolicy {
+	issuerRef := crt.Spec.IssuerRef
+
+	if issuerRef.Kind == "ClusterIssuer" {
+		if c.clusterIssuerLister == nil {
+			return nil
+		}
+		issuer, err := c.clusterIssuerLister.Get(issuerRef.Name)
+		if err != nil {
+			return nil
+		}
+		return issuer.Spec.RenewalPolicy
+	}
+
+	if c.issuerLister == nil {
+		return nil
+	}
+	issuer, err := c.issuerLister.Issuers(crt.Namespace).Get(issuerRef.Name)
+	if err != nil {
+		return nil
+	}
+	return issuer.Spec.RenewalPolicy
+}
+
+// ProcessCertificate is the certificates controller's entry point for a
+// freshly read issued certificate, called once per reconcile after it
+// parses crt's target Secret. A short-duration Warning Event is recorded
+// only the first time a given issued certificate is observed - gated on
+// whether x509Cert's NotAfter differs from the one already recorded in
+// crt.Status - so that neither routine reconciles nor the periodic
+// metrics scraper (which never changes crt.Status) re-fire it for the
+// life of the certificate. The updated NotAfter is persisted via
+// UpdateStatus before returning, so that the gate is honoured across
+// reconciles operating on independently-fetched copies of crt, not just
+// repeated calls against the same in-memory object.
+func (c *Controller) ProcessCertificate(crt *v1alpha1.Certificate, x509Cert *x509.Certificate) {
+	if crt.Status.NotAfter == nil || !crt.Status.NotAfter.Time.Equal(x509Cert.NotAfter) {
+		c.recordShortDurationEventIfNeeded(x509Cert, crt)
+		certmetrics.IncrementRenewals(crt)
+
+		updated := crt.DeepCopy()
+		updated.Status.NotAfter = &metav1.Time{Time: x509Cert.NotAfter}
+		if c.client != nil {
+			persisted, err := c.client.CertmanagerV1alpha1().Certificates(updated.Namespace).UpdateStatus(updated)
+			if err != nil {
+				glog.Errorf("error updating status for certificate %s/%s: %v", crt.Namespace, crt.Name, err)
+			} else {
+				updated = persisted
+			}
+		}
+		crt.Status.NotAfter = updated.Status.NotAfter
+	}
+
+	c.updateMetrics(x509Cert, crt)
+}
+
+// updateMetrics recomputes crt's renewal time and records it, together
+// with x509Cert's expiry, via the certificates metrics package. This is
+// the choke point every codepath that parses an issued certificate or
+// recomputes its renewal time should go through, so that the exported
+// gauges never fall out of sync with what the controller actually acted
+// on.
+func (c *Controller) updateMetrics(x509Cert *x509.Certificate, crt *v1alpha1.Certificate) time.Duration {
+	renewIn := c.calculateTimeBeforeExpiry(x509Cert, crt)
+	certmetrics.Update(crt, x509Cert, renewIn)
+	certmetrics.UpdateReady(crt, isCurrentlyValid(x509Cert))
+	return renewIn
+}
+
+// isCurrentlyValid reports whether x509Cert is within its validity
+// period right now, i.e. whether the certificate it backs can be
+// considered Ready.
+func isCurrentlyValid(x509Cert *x509.Certificate) bool {
+	currentTime := now()
+	return !currentTime.Before(x509Cert.NotBefore) && currentTime.Before(x509Cert.NotAfter)
+}
+
+// calculateJitteredTimeBeforeExpiry implements the randomized soft-expiry
+// window described on calculateTimeBeforeExpiry.
+func calculateJitteredTimeBeforeExpiry(crt *v1alpha1.Certificate, x509Cert *x509.Certificate, certDuration time.Duration) time.Duration {
+	currentTime := now()
+
+	// Already past NotAfter: renew immediately.
+	if !currentTime.Before(x509Cert.NotAfter) {
+		return 0
+	}
+
+	elapsedFraction := float64(currentTime.Sub(x509Cert.NotBefore)) / float64(certDuration)
+
+	// Past the hard-renew mark: renew immediately.
+	if elapsedFraction >= renewalWindowMax {
+		return 0
+	}
+
+	// If we're already inside the soft window, clamp the lower bound to
+	// now so we still pick a time in (now, 90%], rather than one that has
+	// already passed.
+	lowerBound := renewalWindowMin
+	if elapsedFraction > lowerBound {
+		lowerBound = elapsedFraction
+	}
+
+	r := rand.New(rand.NewSource(certificateJitterSeed(crt, x509Cert)))
+	renewalFraction := lowerBound + r.Float64()*(renewalWindowMax-lowerBound)
+	renewAt := x509Cert.NotBefore.Add(time.Duration(renewalFraction * float64(certDuration)))
+
+	return x509Cert.NotAfter.Sub(renewAt)
+}
+
+// shouldEmitShortDurationEvent reports whether x509Cert's actual validity
+// period is meaningfully shorter - by more than
+// issuedDurationShorterThanRequestedThreshold - than crt's requested
+// Spec.Duration. Certificates that don't request an explicit duration
+// have nothing to compare against, so this always returns false for
+// them.
+func shouldEmitShortDurationEvent(x509Cert *x509.Certificate, crt *v1alpha1.Certificate) bool {
+	requested := crt.Spec.Duration.Duration
+	if requested <= 0 {
+		return false
+	}
+
+	actual := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
+	threshold := requested - time.Duration(float64(requested)*issuedDurationShorterThanRequestedThreshold)
+	return actual < threshold
+}
+
+// recordShortDurationEventIfNeeded records a Warning Event against crt
+// when x509Cert was issued with a shorter validity period than crt
+// requested, so that users relying on Spec.Duration notice when an
+// issuer doesn't honour it.
+func (c *Controller) recordShortDurationEventIfNeeded(x509Cert *x509.Certificate, crt *v1alpha1.Certificate) {
+	if c.recorder == nil || !shouldEmitShortDurationEvent(x509Cert, crt) {
+		return
+	}
+
+	actual := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
+	c.recorder.Eventf(crt, corev1.EventTypeWarning, ReasonIssuedDurationShorterThanRequested,
+		"Issued certificate has duration %s which is lower than requested duration %s", actual, crt.Spec.Duration.Duration)
+}
+
+// certificateJitterSeed derives a stable RNG seed from the Certificate's
+// namespace, name and the issued certificate's serial number, so that
+// repeated calls for the same issued certificate always land on the same
+// point within the soft-expiry window.
+func certificateJitterSeed(crt *v1alpha1.Certificate, x509Cert *x509.Certificate) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%s", crt.Namespace, crt.Name, x509Cert.SerialNumber.String())
+	return int64(h.Sum64())
+}
@@ -2,6 +2,8 @@ package certificates
 
 import (
 	"crypto/x509"
+	"fmt"
+	"math/big"
 	"testing"
 	"time"
 
@@ -14,74 +16,121 @@ func TestCalculateTimeBeforeExpiry(t *testing.T) {
 	currentTime := time.Now()
 	now = func() time.Time { return currentTime }
 	defer func() { now = time.Now }()
-	tests := []struct {
-		desc           string
-		notBefore      time.Time
-		notAfter       time.Time
-		duration       time.Duration
-		renewBefore    time.Duration
-		expectedExpiry time.Duration
-	}{
-		{
-			desc:           "generate an event if certificate duration is lower than requested duration",
-			notBefore:      now(),
-			notAfter:       now().Add(time.Hour * 24 * 90),
-			duration:       time.Hour * 24 * 120,
-			renewBefore:    0,
-			expectedExpiry: time.Hour * 24 * 60,
-		},
-		{
-			desc:           "default expiry to 30 days",
-			notBefore:      now(),
-			notAfter:       now().Add(time.Hour * 24 * 120),
-			duration:       0,
-			renewBefore:    0,
-			expectedExpiry: (time.Hour * 24 * 120) - (time.Hour * 24 * 30),
-		},
-		{
-			desc:           "default expiry to 2/3 of total duration if duration < 30 days",
-			notBefore:      now(),
-			notAfter:       now().Add(time.Hour * 24 * 20),
-			duration:       0,
-			renewBefore:    0,
-			expectedExpiry: time.Hour * 24 * 20 * 2 / 3,
-		},
-		{
-			desc:           "expiry of 2/3 of certificate duration when duration < 30 minutes",
-			notBefore:      now(),
-			notAfter:       now().Add(time.Hour),
-			duration:       time.Hour,
-			renewBefore:    time.Hour / 3,
-			expectedExpiry: time.Hour * 2 / 3,
-		},
-		{
-			desc:           "expiry of 60 days of certificate duration",
-			notBefore:      now(),
-			notAfter:       now().Add(time.Hour * 24 * 365),
-			duration:       time.Hour * 24 * 365,
-			renewBefore:    time.Hour * 24 * 60,
-			expectedExpiry: (time.Hour * 24 * 365) - (time.Hour * 24 * 60),
-		},
-		{
-			desc:           "expiry of 2/3 of certificate duration when renewBefore greater than certificate duration",
-			notBefore:      now(),
-			notAfter:       now().Add(time.Hour * 24 * 35),
-			duration:       time.Hour * 24 * 35,
-			renewBefore:    time.Hour * 24 * 40,
-			expectedExpiry: time.Hour * 24 * 35 * 2 / 3,
-		},
-	}
-	for k, v := range tests {
-		cert := &v1alpha1.Certificate{
-			Spec: v1alpha1.CertificateSpec{
-				Duration:    metav1.Duration{v.duration},
-				RenewBefore: metav1.Duration{v.renewBefore},
+
+	t.Run("RenewBefore is set", func(t *testing.T) {
+		tests := []struct {
+			desc           string
+			notBefore      time.Time
+			notAfter       time.Time
+			duration       time.Duration
+			renewBefore    time.Duration
+			expectedExpiry time.Duration
+		}{
+			{
+				desc:           "default expiry to 2/3 of certificate duration when renewBefore greater than certificate duration",
+				notBefore:      now(),
+				notAfter:       now().Add(time.Hour * 24 * 35),
+				duration:       time.Hour * 24 * 35,
+				renewBefore:    time.Hour * 24 * 40,
+				expectedExpiry: time.Hour * 24 * 35 / 3,
+			},
+			{
+				desc:           "expiry of 60 days before certificate expiry",
+				notBefore:      now(),
+				notAfter:       now().Add(time.Hour * 24 * 365),
+				duration:       time.Hour * 24 * 365,
+				renewBefore:    time.Hour * 24 * 60,
+				expectedExpiry: time.Hour * 24 * 60,
+			},
+			{
+				desc:           "expiry of 2/3 of certificate duration when duration is very short",
+				notBefore:      now(),
+				notAfter:       now().Add(time.Hour),
+				duration:       time.Hour,
+				renewBefore:    time.Hour / 3,
+				expectedExpiry: time.Hour / 3,
+			},
+		}
+		for k, v := range tests {
+			cert := &v1alpha1.Certificate{
+				Spec: v1alpha1.CertificateSpec{
+					Duration:    metav1.Duration{Duration: v.duration},
+					RenewBefore: metav1.Duration{Duration: v.renewBefore},
+				},
+			}
+			x509Cert := &x509.Certificate{NotBefore: v.notBefore, NotAfter: v.notAfter, SerialNumber: big.NewInt(int64(k) + 1)}
+			duration := c.calculateTimeBeforeExpiry(x509Cert, cert)
+			if duration != v.expectedExpiry {
+				t.Errorf("test # %d - %s: got %v, expected %v", k, v.desc, duration, v.expectedExpiry)
+			}
+		}
+	})
+
+	// When RenewBefore is unset, renewal is jittered uniformly within the
+	// 60%-90% soft-expiry window, so these assert a range rather than an
+	// exact value.
+	t.Run("RenewBefore is unset, renewal is jittered", func(t *testing.T) {
+		tests := []struct {
+			desc      string
+			notBefore time.Time
+			notAfter  time.Time
+			duration  time.Duration
+			minExpiry time.Duration
+			maxExpiry time.Duration
+		}{
+			{
+				desc:      "duration mismatched against actual issued lifetime still falls in the soft window",
+				notBefore: now(),
+				notAfter:  now().Add(time.Hour * 24 * 90),
+				duration:  time.Hour * 24 * 120,
+				minExpiry: time.Hour * 24 * 90 * 1 / 10,
+				maxExpiry: time.Hour * 24 * 90 * 4 / 10,
+			},
+			{
+				desc:      "just issued: renewal falls somewhere in the 60%-90% soft window",
+				notBefore: now(),
+				notAfter:  now().Add(time.Hour * 24 * 120),
+				duration:  0,
+				minExpiry: time.Hour * 24 * 120 * 1 / 10,
+				maxExpiry: time.Hour * 24 * 120 * 4 / 10,
+			},
+			{
+				desc:      "already in the soft window: lower bound is clamped to now",
+				notBefore: now().Add(-time.Hour * 24 * 80),
+				notAfter:  now().Add(time.Hour * 24 * 40),
+				duration:  0,
+				minExpiry: time.Hour * 24 * 12,
+				maxExpiry: time.Hour * 24 * 40,
+			},
+			{
+				desc:      "already past the hard-renew mark: renew immediately",
+				notBefore: now().Add(-time.Hour * 24 * 110),
+				notAfter:  now().Add(time.Hour * 24 * 10),
+				duration:  0,
+				minExpiry: 0,
+				maxExpiry: 0,
+			},
+			{
+				desc:      "already expired: renew immediately",
+				notBefore: now().Add(-time.Hour * 24 * 130),
+				notAfter:  now().Add(-time.Hour * 24 * 10),
+				duration:  0,
+				minExpiry: 0,
+				maxExpiry: 0,
 			},
 		}
-		x509Cert := &x509.Certificate{NotBefore: v.notBefore, NotAfter: v.notAfter}
-		duration := c.calculateTimeBeforeExpiry(x509Cert, cert)
-		if duration != v.expectedExpiry {
-			t.Errorf("test # %d - %s: got %v, expected %v", k, v.desc, duration, v.expectedExpiry)
+		for k, v := range tests {
+			cert := &v1alpha1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: fmt.Sprintf("cert-%d", k)},
+				Spec: v1alpha1.CertificateSpec{
+					Duration: metav1.Duration{Duration: v.duration},
+				},
+			}
+			x509Cert := &x509.Certificate{NotBefore: v.notBefore, NotAfter: v.notAfter, SerialNumber: big.NewInt(int64(k) + 1)}
+			duration := c.calculateTimeBeforeExpiry(x509Cert, cert)
+			if duration < v.minExpiry || duration > v.maxExpiry {
+				t.Errorf("test # %d - %s: got %v, expected between %v and %v", k, v.desc, duration, v.minExpiry, v.maxExpiry)
+			}
 		}
-	}
-}
\ No newline at end of file
+	})
+}
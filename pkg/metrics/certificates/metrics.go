@@ -0,0 +1,106 @@
+// Package certificates exports Prometheus metrics describing the
+// certificates controller's view of Certificate expiry and renewal
+// scheduling.
+package certificates
+
+import (
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+)
+
+var labelNames = []string{"namespace", "name", "issuer"}
+
+var (
+	certificateExpiryTimeSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "certmanager_certificate_expiration_timestamp_seconds",
+			Help: "The date after which the certificate expires, as a Unix timestamp.",
+		},
+		labelNames,
+	)
+
+	certificateReadyStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "certmanager_certificate_ready_status",
+			Help: "The ready status of the certificate, 1 for Ready, 0 otherwise.",
+		},
+		labelNames,
+	)
+
+	certificateRenewalTimeSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "certmanager_certificate_renewal_timestamp_seconds",
+			Help: "The Unix timestamp at which the certificate is scheduled to be renewed.",
+		},
+		labelNames,
+	)
+
+	certificateRenewalsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "certmanager_certificate_renewals_total",
+			Help: "The number of times a certificate has actually been renewed or issued.",
+		},
+		labelNames,
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		certificateExpiryTimeSeconds,
+		certificateReadyStatus,
+		certificateRenewalTimeSeconds,
+		certificateRenewalsTotal,
+	)
+}
+
+// Update records x509Cert's expiry and its computed renewal time -
+// NotAfter minus renewIn - for crt. It should be called whenever the
+// certificates controller parses an issued certificate or recomputes its
+// renewal time, including from the periodic metrics scraper, so it does
+// not touch certificateRenewalsTotal - use IncrementRenewals for that.
+func Update(crt *v1alpha1.Certificate, x509Cert *x509.Certificate, renewIn time.Duration) {
+	labels := certificateLabels(crt)
+
+	certificateExpiryTimeSeconds.With(labels).Set(float64(x509Cert.NotAfter.Unix()))
+	certificateRenewalTimeSeconds.With(labels).Set(float64(x509Cert.NotAfter.Add(-renewIn).Unix()))
+}
+
+// UpdateReady records ready as crt's ready status: 1 if true, 0
+// otherwise. It does not look at crt's conditions itself.
+func UpdateReady(crt *v1alpha1.Certificate, ready bool) {
+	v := float64(0)
+	if ready {
+		v = 1
+	}
+	certificateReadyStatus.With(certificateLabels(crt)).Set(v)
+}
+
+// IncrementRenewals increments the renewals counter for crt. Unlike
+// Update, this should be called only when a certificate has actually
+// been (re)issued, not on every recomputation of its renewal time.
+func IncrementRenewals(crt *v1alpha1.Certificate) {
+	certificateRenewalsTotal.With(certificateLabels(crt)).Inc()
+}
+
+func certificateLabels(crt *v1alpha1.Certificate) prometheus.Labels {
+	return prometheus.Labels{
+		"namespace": crt.Namespace,
+		"name":      crt.Name,
+		"issuer":    crt.Spec.IssuerRef.Name,
+	}
+}
+
+// RegisterHandler registers the Prometheus scrape handler on mux at path.
+// An empty path defaults to "/metrics".
+func RegisterHandler(mux *http.ServeMux, path string) {
+	if path == "" {
+		path = "/metrics"
+	}
+	mux.Handle(path, promhttp.Handler())
+}
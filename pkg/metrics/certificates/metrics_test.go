@@ -0,0 +1,66 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdate(t *testing.T) {
+	notBefore := time.Now()
+	notAfter := notBefore.Add(time.Hour * 24 * 365)
+	renewIn := time.Hour * 24 * 60
+
+	crt := &v1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-1"},
+		Spec:       v1alpha1.CertificateSpec{IssuerRef: v1alpha1.ObjectReference{Name: "issuer-1"}},
+	}
+	x509Cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter, SerialNumber: big.NewInt(1)}
+
+	Update(crt, x509Cert, renewIn)
+
+	if got, want := testutil.ToFloat64(certificateExpiryTimeSeconds.WithLabelValues("ns1", "cert-1", "issuer-1")), float64(notAfter.Unix()); got != want {
+		t.Errorf("unexpected expiry gauge value: got %v, want %v", got, want)
+	}
+
+	if got, want := testutil.ToFloat64(certificateRenewalTimeSeconds.WithLabelValues("ns1", "cert-1", "issuer-1")), float64(notAfter.Add(-renewIn).Unix()); got != want {
+		t.Errorf("unexpected renewal gauge value: got %v, want %v", got, want)
+	}
+}
+
+func TestIncrementRenewals(t *testing.T) {
+	crt := &v1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-3"},
+		Spec:       v1alpha1.CertificateSpec{IssuerRef: v1alpha1.ObjectReference{Name: "issuer-1"}},
+	}
+
+	IncrementRenewals(crt)
+	IncrementRenewals(crt)
+
+	if got, want := testutil.ToFloat64(certificateRenewalsTotal.WithLabelValues("ns1", "cert-3", "issuer-1")), float64(2); got != want {
+		t.Errorf("unexpected renewals counter value: got %v, want %v", got, want)
+	}
+}
+
+func TestUpdateReady(t *testing.T) {
+	crt := &v1alpha1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cert-2"},
+		Spec:       v1alpha1.CertificateSpec{IssuerRef: v1alpha1.ObjectReference{Name: "issuer-1"}},
+	}
+
+	UpdateReady(crt, true)
+	if got, want := testutil.ToFloat64(certificateReadyStatus.WithLabelValues("ns1", "cert-2", "issuer-1")), float64(1); got != want {
+		t.Errorf("unexpected ready status: got %v, want %v", got, want)
+	}
+
+	UpdateReady(crt, false)
+	if got, want := testutil.ToFloat64(certificateReadyStatus.WithLabelValues("ns1", "cert-2", "issuer-1")), float64(0); got != want {
+		t.Errorf("unexpected ready status: got %v, want %v", got, want)
+	}
+}